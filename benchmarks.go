@@ -29,10 +29,15 @@ package benchmarks
 import (
 	"fmt"
 	"github.com/streadway/quantile"
+	"io"
+	"os"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-	"unicode/utf8"
 )
 
 type Options struct {
@@ -41,14 +46,126 @@ type Options struct {
 	quantiles             []int
 	warmUps, innerRepeats int
 	duration              time.Duration
+	iterations            int
 	tolerance             float64
 	columnSize            int
+	memStats              bool
+	parallelism           int
+	gomaxprocsSweep       []int
+	rawSamples            bool
+	format                Format
+	writer                io.Writer
+	densityPlot           bool
+	svgOutputPath         string
+	autoInnerRepeats      bool
 }
 
+// Format selects how Options.Done renders the results of a benchmark run. See WithFormat.
+type Format int
+
+const (
+	// FormatText renders a human-readable, tab-aligned table. This is the default.
+	FormatText Format = iota
+	// FormatJSON renders the results as a JSON array of Result.
+	FormatJSON
+	// FormatCSV renders the results as comma-separated values, one row per Result.
+	FormatCSV
+	// FormatGoBench renders the results using the `go test -bench` output format, consumable by benchstat:
+	// "BenchmarkXxx-8    12345    678.00 ns/op    12 B/op    3 allocs/op".
+	FormatGoBench
+)
+
 // NamedFunction holds a function to be benchmarked and its name.
+//
+// Exactly one of Func, FuncB or FuncPB should be set. Use FuncB -- which takes a *B handle -- when the
+// function needs to exclude setup cost (opening files, allocating fixtures) from the timing, using
+// B.StartTimer, B.StopTimer and B.ResetTimer. Use FuncPB -- which takes a *PB handle -- to benchmark the
+// function under concurrent load across multiple goroutines; see Options.WithParallelism.
 type NamedFunction struct {
-	Name string
-	Func func()
+	Name   string
+	Func   func()
+	FuncB  func(b *B)
+	FuncPB func(pb *PB)
+}
+
+// B is the handle passed to a NamedFunction.FuncB, mirroring testing.B's timer controls. The timer starts
+// enabled: call StopTimer before expensive setup and StartTimer once the code to be measured begins.
+type B struct {
+	timerOn  bool
+	start    time.Time
+	duration time.Duration
+}
+
+// StartTimer resumes the timer. It is a no-op if the timer is already running.
+func (b *B) StartTimer() {
+	if !b.timerOn {
+		b.start = time.Now()
+		b.timerOn = true
+	}
+}
+
+// StopTimer pauses the timer, accumulating the elapsed time since the last StartTimer. It is a no-op if the
+// timer is already stopped.
+func (b *B) StopTimer() {
+	if b.timerOn {
+		b.duration += time.Since(b.start)
+		b.timerOn = false
+	}
+}
+
+// ResetTimer zeroes the elapsed duration accumulated so far, without changing whether the timer is running.
+func (b *B) ResetTimer() {
+	b.duration = 0
+	if b.timerOn {
+		b.start = time.Now()
+	}
+}
+
+// PB is the handle passed to a NamedFunction.FuncPB, mirroring testing.B.RunParallel's PB. Each goroutine
+// spawned for a parallel benchmark gets its own PB; a typical FuncPB looks like:
+//
+//	func(pb *benchmarks.PB) {
+//		for pb.Next() {
+//			MyFunc()
+//		}
+//	}
+type PB struct {
+	done      <-chan struct{}
+	mu        *sync.Mutex
+	estimator *quantile.Estimator
+	totalTime *int64
+	count     *int64
+
+	// rawSamples, when non-nil, accumulates every per-work-item duration (in nanoseconds), mirroring the
+	// sequential path's samples slice; only populated when Options.rawSamples is set. Guarded by mu.
+	rawSamples *[]float64
+
+	lastStart time.Time
+}
+
+// Next reports whether there is more work to do: the caller's loop should keep calling MyFunc while Next
+// returns true. It also records, into the shared estimator, the elapsed time since the previous call to
+// Next returned true -- i.e. the time spent on the work item the caller just finished.
+func (pb *PB) Next() bool {
+	now := time.Now()
+	if !pb.lastStart.IsZero() {
+		elapsed := now.Sub(pb.lastStart)
+		pb.mu.Lock()
+		pb.estimator.Add(float64(elapsed) / float64(time.Nanosecond))
+		if pb.rawSamples != nil {
+			*pb.rawSamples = append(*pb.rawSamples, float64(elapsed))
+		}
+		pb.mu.Unlock()
+		atomic.AddInt64(pb.totalTime, int64(elapsed))
+		atomic.AddInt64(pb.count, 1)
+	}
+	select {
+	case <-pb.done:
+		return false
+	default:
+		pb.lastStart = now
+		return true
+	}
 }
 
 // DefaultQuantiles to use in benchmarking. It can be changed for a particular benchmark using Options.WithQuantiles.
@@ -90,6 +207,9 @@ func New(fns ...NamedFunction) *Options {
 		duration:      1 * time.Second,
 		tolerance:     0.001,
 		columnSize:    10,
+		parallelism:   1,
+		format:        FormatText,
+		writer:        os.Stdout,
 	}
 }
 
@@ -122,19 +242,85 @@ func (o *Options) WithWarmUps(warmUps int) *Options {
 //
 // Notice that reported measures are divided by this number. That means changing this number shouldn't
 // affect the reported mean.
+//
+// This is mutually exclusive with WithAutoInnerRepeats: whichever is called last wins.
 func (o *Options) WithInnerRepeats(innerRepeats int) *Options {
 	o.innerRepeats = innerRepeats
+	o.autoInnerRepeats = false
+	return o
+}
+
+// WithAutoInnerRepeats replaces a manual WithInnerRepeats choice with automatic calibration: before
+// collecting samples, each NamedFunction.Func is probed with a doubling inner-repeat count (1, 2, 4, ...)
+// until a single probe call takes at least autoInnerRepeatsFloor, removing the need to hand-write an inner
+// loop for sub-microsecond functions. Reported means are still divided by the calibrated count.
+//
+// Only NamedFunction.Func is calibrated this way; FuncB and FuncPB benchmarks are unaffected, since they
+// have their own ways to exclude overhead from the timing (B.StartTimer/StopTimer, PB.Next).
+//
+// This is mutually exclusive with WithInnerRepeats: whichever is called last wins.
+func (o *Options) WithAutoInnerRepeats() *Options {
+	o.autoInnerRepeats = true
+	o.innerRepeats = 1
 	return o
 }
 
 // WithDuration sets the benchmark duration for each function for the options and returns the updated Options instance.
 // When running the benchmark (Options.Done) it will run each function for at least this amount time, collecting
 // statistics.
+//
+// This is mutually exclusive with WithIterations: whichever is called last wins.
 func (o *Options) WithDuration(duration time.Duration) *Options {
 	o.duration = duration
+	o.iterations = 0
 	return o
 }
 
+// WithIterations sets a fixed number of iterations to run for each function, instead of running for a fixed
+// duration. This makes measurements reproducible across machines for very fast functions, where a wall-clock
+// budget (WithDuration) would otherwise yield wildly varying sample counts.
+//
+// Only NamedFunction.Func and FuncB benchmarks run a fixed number of iterations; FuncPB benchmarks always
+// run for the WithDuration budget, which this sets to 0.
+//
+// This is mutually exclusive with WithDuration: whichever is called last wins.
+func (o *Options) WithIterations(n int) *Options {
+	o.iterations = n
+	o.duration = 0
+	return o
+}
+
+// ParseBenchTime parses a `-benchtime`-style flag value, accepting either a duration (e.g. "2s") or a fixed
+// iteration count in the form "<N>x" (e.g. "1000x"). It returns the parsed duration, or the parsed iteration
+// count, with the other left at its zero value.
+func ParseBenchTime(s string) (duration time.Duration, iterations int, err error) {
+	if n, ok := strings.CutSuffix(s, "x"); ok {
+		iterations, err = strconv.Atoi(n)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid iteration count %q: %w", s, err)
+		}
+		return 0, iterations, nil
+	}
+	duration, err = time.ParseDuration(s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bench time %q: %w", s, err)
+	}
+	return duration, 0, nil
+}
+
+// WithBenchTime parses s with ParseBenchTime and applies the result with WithDuration or WithIterations,
+// whichever it resolves to. It returns the updated Options instance, or an error if s can't be parsed.
+func (o *Options) WithBenchTime(s string) (*Options, error) {
+	duration, iterations, err := ParseBenchTime(s)
+	if err != nil {
+		return o, err
+	}
+	if iterations > 0 {
+		return o.WithIterations(iterations), nil
+	}
+	return o.WithDuration(duration), nil
+}
+
 // WithTolerance sets the tolerance in the approximate quantiles calculations. The smaller the tolerance the larger
 // the amount of memory used in approximating the quantiles -- which may impact the running time due to GC (??)
 //
@@ -154,110 +340,383 @@ func (o *Options) WithColumnSize(columnSize int) *Options {
 	return o
 }
 
+// WithMemStats enables allocation reporting, similar to go test's `-benchmem` flag. When enabled, Done()
+// reports two extra columns: allocs/op and bytes/op.
+//
+// runtime.MemStats is read once before and once after the whole sample collection of a function, and the
+// difference is divided by the number of calls; it is not read around each individual invocation.
+// NamedFunction.FuncPB benchmarks are not measured and report 0 for both columns.
+func (o *Options) WithMemStats() *Options {
+	o.memStats = true
+	return o
+}
+
+// WithParallelism sets, for NamedFunction.FuncPB benchmarks, how many goroutines to run concurrently as a
+// multiplier of runtime.GOMAXPROCS(0) -- i.e. p*GOMAXPROCS(0) goroutines are spawned, mirroring
+// testing.B.SetParallelism. Default is 1.
+func (o *Options) WithParallelism(p int) *Options {
+	o.parallelism = p
+	return o
+}
+
+// WithGOMAXPROCSSweep sets a list of runtime.GOMAXPROCS values to sweep over for NamedFunction.FuncPB
+// benchmarks: each function is run once per value in values, restoring the previous GOMAXPROCS setting
+// afterwards, and Done() reports one row per (function, GOMAXPROCS) combination. Handy for benchmarking
+// contention-sensitive code (mutexes, sync.Pool, atomics) across core counts.
+func (o *Options) WithGOMAXPROCSSweep(values ...int) *Options {
+	o.gomaxprocsSweep = slices.Clone(values)
+	return o
+}
+
+// WithRawSamples enables retaining every per-iteration duration sampled during the benchmark (rather than
+// only feeding them into the streaming quantile estimator), so that Done() can additionally report
+// bootstrap confidence intervals for the mean and median, and classify outlier-induced variance.
+//
+// This is opt-in because it makes memory usage proportional to the number of iterations collected, instead
+// of bounded by WithTolerance.
+func (o *Options) WithRawSamples(enable bool) *Options {
+	o.rawSamples = enable
+	return o
+}
+
+// WithFormat sets the rendering format used by Done(). Default is FormatText.
+func (o *Options) WithFormat(format Format) *Options {
+	o.format = format
+	return o
+}
+
+// WithWriter sets the writer Done() renders the results to. Default is os.Stdout.
+func (o *Options) WithWriter(w io.Writer) *Options {
+	o.writer = w
+	return o
+}
+
+// WithDensityPlot enables computing a kernel density estimate of each function's per-iteration durations,
+// surfaced as a compact ASCII sparkline column in Done()'s FormatText output (and in Result.DensityX /
+// Result.DensityY for programmatic use via Run()). This surfaces bimodal timings -- e.g. GC pauses, cache
+// effects -- that a mean+quantile summary hides.
+//
+// It implies WithRawSamples(true): raw per-iteration samples must be retained to estimate the density.
+func (o *Options) WithDensityPlot() *Options {
+	o.densityPlot = true
+	o.rawSamples = true
+	return o
+}
+
+// WithSVGOutput enables WithDensityPlot and additionally writes an SVG file to path, once Done() finishes,
+// with one density curve per function overlaid on a shared axis.
+func (o *Options) WithSVGOutput(path string) *Options {
+	o.WithDensityPlot()
+	o.svgOutputPath = path
+	return o
+}
+
 func nanosecondsEstimate(est *quantile.Estimator, quantile float64) time.Duration {
 	return time.Duration(int(est.Get(quantile))) * time.Nanosecond
 }
 
-type results struct {
-	mean, median time.Duration
-	quantiles    []time.Duration
-	count        int
+// callOnce runs namedFn once and returns the elapsed time to attribute to it: for NamedFunction.Func this is
+// simply the wall-clock time of the call; for NamedFunction.FuncB it is the duration accumulated by b between
+// StartTimer/StopTimer calls, so setup code wrapped in StopTimer/StartTimer is excluded.
+func callOnce(namedFn NamedFunction) time.Duration {
+	if namedFn.FuncB != nil {
+		b := &B{}
+		b.StartTimer()
+		namedFn.FuncB(b)
+		b.StopTimer()
+		return b.duration
+	}
+	start := time.Now()
+	namedFn.Func()
+	return time.Since(start)
+}
+
+// autoInnerRepeatsFloor is the minimum duration a single (possibly wrapped) call must take for
+// calibrateInnerRepeats to accept its inner-repeat count: roughly 10x the resolution of time.Now on common
+// platforms, so the repeat count itself contributes negligible noise to the measurement.
+const autoInnerRepeatsFloor = 100 * time.Microsecond
+
+// maxAutoInnerRepeats caps the doubling search in calibrateInnerRepeats, so a function that never reaches
+// autoInnerRepeatsFloor (e.g. one whose per-call cost is close to zero) can't loop forever.
+const maxAutoInnerRepeats = 1 << 20
+
+// calibrateInnerRepeats finds the smallest power-of-two inner-repeat count n for which wrapping
+// namedFn.Func in a `for i := 0; i < n; i++` loop takes at least autoInnerRepeatsFloor to run once. It
+// probes in place: each candidate n is timed directly, without yet constructing the wrapped NamedFunction.
+func calibrateInnerRepeats(namedFn NamedFunction) int {
+	for n := 1; n < maxAutoInnerRepeats; n *= 2 {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			namedFn.Func()
+		}
+		if time.Since(start) >= autoInnerRepeatsFloor {
+			return n
+		}
+	}
+	return maxAutoInnerRepeats
+}
+
+// wrapNamedFunction returns a copy of namedFn whose Func calls the original Func n times in a loop. It is a
+// no-op (returns namedFn unchanged) when n <= 1.
+func wrapNamedFunction(namedFn NamedFunction, n int) NamedFunction {
+	if n <= 1 {
+		return namedFn
+	}
+	inner := namedFn.Func
+	namedFn.Func = func() {
+		for i := 0; i < n; i++ {
+			inner()
+		}
+	}
+	return namedFn
+}
+
+// Result holds the structured outcome of benchmarking one NamedFunction, as returned by Options.Run.
+type Result struct {
+	// Name is the function's name, suffixed with "/GOMAXPROCS=N" for NamedFunction.FuncPB rows whenever
+	// WithGOMAXPROCSSweep is set (even with a single value) -- following Go's own sub-benchmark naming convention, so
+	// FormatGoBench output stays groupable by benchstat.
+	Name         string
+	Mean, Median time.Duration
+	// GOMAXPROCS is the runtime.GOMAXPROCS(0) value this Result was collected under.
+	GOMAXPROCS       int
+	QuantilePercents []int
+	Quantiles        []time.Duration
+	Count            int
+	NetAllocs        uint64
+	NetBytes         uint64
+
+	// MeanCI, MedianCI and OutlierEffect are only set when Options.WithRawSamples(true) is used.
+	MeanCI, MedianCI [2]time.Duration
+	OutlierEffect    string
+
+	// DensityX and DensityY hold the kernel density estimate of per-iteration durations, only set when
+	// Options.WithDensityPlot is used. DensitySparkline is a compact ASCII rendering of the same data.
+	DensityX         []time.Duration
+	DensityY         []float64
+	DensitySparkline string
 }
 
-func (o *Options) benchmarkOneFunc(fn func()) results {
-	// Estimates for median & other quantiles
+// newEstimator builds the quantile.Estimator used to track the median and the configured quantiles.
+func (o *Options) newEstimator() *quantile.Estimator {
 	estimates := make([]quantile.Estimate, 0, len(o.quantiles)+1)
 	estimates = append(estimates, quantile.Known(0.50, o.tolerance))
 	for _, pct := range o.quantiles {
 		estimates = append(estimates, quantile.Known(float64(pct)/100.0, o.tolerance))
 	}
+	return quantile.New(estimates...)
+}
 
+func (o *Options) benchmarkOneFunc(namedFn NamedFunction) Result {
 	// Estimator for quantiles and mean.
-	estimator := quantile.New(estimates...)
+	estimator := o.newEstimator()
 	var totalTime time.Duration
 	var count int
-	timer := time.NewTimer(o.duration)
-
-collection:
-	for {
-		select {
-		case <-timer.C:
-			break collection
-		default:
-			start := time.Now()
-			fn()
-			elapsed := time.Since(start)
+	var samples []float64 // nanoseconds; only populated when o.rawSamples is set.
+
+	var memStatsBefore, memStatsAfter runtime.MemStats
+	if o.memStats {
+		runtime.ReadMemStats(&memStatsBefore)
+	}
+
+	if o.iterations > 0 {
+		for count = 0; count < o.iterations; count++ {
+			elapsed := callOnce(namedFn)
 			estimator.Add(float64(elapsed) / float64(time.Nanosecond))
 			totalTime += elapsed
-			count++
+			if o.rawSamples {
+				samples = append(samples, float64(elapsed))
+			}
+		}
+	} else {
+		timer := time.NewTimer(o.duration)
+	collection:
+		for {
+			select {
+			case <-timer.C:
+				break collection
+			default:
+				elapsed := callOnce(namedFn)
+				estimator.Add(float64(elapsed) / float64(time.Nanosecond))
+				totalTime += elapsed
+				count++
+				if o.rawSamples {
+					samples = append(samples, float64(elapsed))
+				}
+			}
 		}
 	}
 
 	// Convert estimates back to time.Duration.
-	r := results{
-		mean:      totalTime / time.Duration(count),
-		median:    nanosecondsEstimate(estimator, 0.50),
-		quantiles: make([]time.Duration, len(o.quantiles)),
-		count:     count,
+	r := Result{
+		Mean:             totalTime / time.Duration(count),
+		Median:           nanosecondsEstimate(estimator, 0.50),
+		QuantilePercents: slices.Clone(o.quantiles),
+		Quantiles:        make([]time.Duration, len(o.quantiles)),
+		Count:            count,
 	}
 	for i, pct := range o.quantiles {
-		r.quantiles[i] = nanosecondsEstimate(estimator, float64(pct)/100.0)
+		r.Quantiles[i] = nanosecondsEstimate(estimator, float64(pct)/100.0)
+	}
+
+	if o.memStats {
+		runtime.ReadMemStats(&memStatsAfter)
+		r.NetAllocs = (memStatsAfter.Mallocs - memStatsBefore.Mallocs) / uint64(count)
+		r.NetBytes = (memStatsAfter.TotalAlloc - memStatsBefore.TotalAlloc) / uint64(count)
+	}
+
+	if o.rawSamples {
+		r.MeanCI = bcaConfidenceInterval(samples, meanStatistic)
+		r.MedianCI = bcaConfidenceInterval(samples, medianStatistic)
+		r.OutlierEffect = classifyOutlierVariance(samples)
+	}
+
+	if o.densityPlot {
+		r.DensityX, r.DensityY = kernelDensityEstimate(samples)
+		r.DensitySparkline = sparklineFromDensity(r.DensityY)
 	}
 	return r
 }
 
-func (o *Options) Done() {
-	// First column
-	header := "Benchmarks:"
-	maxLen := len(header)
-	runeCount := make([]int, len(o.fns))
-	for ii, namedFn := range o.fns {
-		runeCount[ii] = utf8.RuneCountInString(namedFn.Name)
-		maxLen = max(maxLen, runeCount[ii])
+// benchmarkOneFuncParallel runs namedFn.FuncPB across p*runtime.GOMAXPROCS(0) goroutines for o.duration,
+// funneling per-work-item durations reported by PB.Next into a single lock-protected estimator. When
+// o.rawSamples is set, it also retains every per-work-item duration to compute bootstrap confidence
+// intervals and outlier classification, and, when o.densityPlot is set, a kernel density estimate --
+// exactly as the sequential path (benchmarkOneFunc) does.
+func (o *Options) benchmarkOneFuncParallel(namedFn NamedFunction, p int) Result {
+	estimator := o.newEstimator()
+	var mu sync.Mutex
+	var totalTime, count int64
+	var samples []float64 // nanoseconds; only populated when o.rawSamples is set.
+
+	done := make(chan struct{})
+	time.AfterFunc(o.duration, func() { close(done) })
+
+	numGoroutines := p * runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+			pb := &PB{done: done, mu: &mu, estimator: estimator, totalTime: &totalTime, count: &count}
+			if o.rawSamples {
+				pb.rawSamples = &samples
+			}
+			namedFn.FuncPB(pb)
+		}()
 	}
+	wg.Wait()
 
-	// Header
-	extraSpaces := maxLen - len(header)
-	if extraSpaces > 0 {
-		header = header + strings.Repeat(" ", extraSpaces)
+	if count == 0 {
+		// Every PB only reports a sample from its second Next() call onward, so a duration too short
+		// relative to a single unit of work (or an unlucky GOMAXPROCS sweep entry) can leave count at 0.
+		return Result{QuantilePercents: slices.Clone(o.quantiles), Quantiles: make([]time.Duration, len(o.quantiles))}
 	}
-	fmt.Printf("%s\t%*s\t%*s", header, o.columnSize, "Mean", o.columnSize, "Median")
-	for _, q := range o.quantiles {
-		fmt.Printf("\t%*s", o.columnSize, fmt.Sprintf("%d%%-tile", q))
+
+	r := Result{
+		Mean:             time.Duration(totalTime) / time.Duration(count),
+		Median:           nanosecondsEstimate(estimator, 0.50),
+		QuantilePercents: slices.Clone(o.quantiles),
+		Quantiles:        make([]time.Duration, len(o.quantiles)),
+		Count:            int(count),
+	}
+	for i, pct := range o.quantiles {
+		r.Quantiles[i] = nanosecondsEstimate(estimator, float64(pct)/100.0)
+	}
+
+	if o.rawSamples {
+		r.MeanCI = bcaConfidenceInterval(samples, meanStatistic)
+		r.MedianCI = bcaConfidenceInterval(samples, medianStatistic)
+		r.OutlierEffect = classifyOutlierVariance(samples)
 	}
-	countStr := "Count"
-	if o.innerRepeats > 1 {
-		countStr = fmt.Sprintf("Runs(x%d)", o.innerRepeats)
+
+	if o.densityPlot {
+		r.DensityX, r.DensityY = kernelDensityEstimate(samples)
+		r.DensitySparkline = sparklineFromDensity(r.DensityY)
 	}
-	fmt.Printf("\t%*s\n", o.columnSize, countStr)
+	return r
+}
+
+// Run executes the benchmark for every configured NamedFunction -- sweeping over GOMAXPROCS for FuncPB
+// functions -- and returns one Result per (function, setting) combination, in the order functions were
+// given to New. Unlike Done, it performs no rendering: use it to consume results programmatically.
+func (o *Options) Run() []Result {
+	results := make([]Result, 0, len(o.fns))
+	for _, namedFn := range o.fns {
+		if namedFn.FuncPB != nil {
+			procsList := o.gomaxprocsSweep
+			if len(procsList) == 0 {
+				procsList = []int{runtime.GOMAXPROCS(0)}
+			}
+			for _, gmp := range procsList {
+				prevGMP := runtime.GOMAXPROCS(gmp)
+				r := o.benchmarkOneFuncParallel(namedFn, o.parallelism)
+				runtime.GOMAXPROCS(prevGMP)
+				r.Name = namedFn.Name
+				r.GOMAXPROCS = gmp
+				if len(o.gomaxprocsSweep) > 0 {
+					r.Name = fmt.Sprintf("%s/GOMAXPROCS=%d", r.Name, gmp)
+				}
+				results = append(results, r)
+			}
+			continue
+		}
+
+		fn := namedFn
+		repeats := o.innerRepeats
+		if o.autoInnerRepeats && namedFn.Func != nil {
+			repeats = calibrateInnerRepeats(namedFn)
+			fn = wrapNamedFunction(namedFn, repeats)
+		}
 
-	for ii, namedFn := range o.fns {
 		// Warm-up
 		for _ = range o.warmUps {
-			namedFn.Func()
+			callOnce(fn)
 		}
 
 		// Collect benchmark estimations.
-		r := o.benchmarkOneFunc(namedFn.Func)
-		repeats := o.innerRepeats
+		r := o.benchmarkOneFunc(fn)
 		if repeats > 1 {
-			r.mean /= time.Duration(repeats)
-			r.median /= time.Duration(repeats)
-			for ii := range r.quantiles {
-				r.quantiles[ii] /= time.Duration(repeats)
+			r.Mean /= time.Duration(repeats)
+			r.Median /= time.Duration(repeats)
+			for ii := range r.Quantiles {
+				r.Quantiles[ii] /= time.Duration(repeats)
+			}
+			r.NetAllocs /= uint64(repeats)
+			r.NetBytes /= uint64(repeats)
+			r.MeanCI[0] /= time.Duration(repeats)
+			r.MeanCI[1] /= time.Duration(repeats)
+			r.MedianCI[0] /= time.Duration(repeats)
+			r.MedianCI[1] /= time.Duration(repeats)
+			for ii := range r.DensityX {
+				r.DensityX[ii] /= time.Duration(repeats)
 			}
 		}
+		r.Name = namedFn.Name
+		r.GOMAXPROCS = runtime.GOMAXPROCS(0)
+		results = append(results, r)
+	}
+	return results
+}
 
-		// Pretty-print.
-		name := namedFn.Name
-		extraSpaces := maxLen - runeCount[ii]
-		if extraSpaces > 0 {
-			name = name + strings.Repeat(" ", extraSpaces)
-		}
-		fmt.Printf("%s\t%*s\t%*s", name, o.columnSize, o.prettyPrintFn(r.mean), o.columnSize, o.prettyPrintFn(r.median))
-		for _, q := range r.quantiles {
-			fmt.Printf("\t%*s", o.columnSize, o.prettyPrintFn(q))
+// Done runs the benchmark, like Run, and renders the results to o.writer in o.format. This is the
+// entry point for command-line-style usage; see Run for programmatic consumption of the results.
+func (o *Options) Done() {
+	results := o.Run()
+	switch o.format {
+	case FormatJSON:
+		o.renderJSON(results)
+	case FormatCSV:
+		o.renderCSV(results)
+	case FormatGoBench:
+		o.renderGoBench(results)
+	default:
+		o.renderText(results)
+	}
+
+	if o.svgOutputPath != "" {
+		if err := writeDensitySVG(o.svgOutputPath, results); err != nil {
+			fmt.Fprintf(o.writer, "benchmarks: failed to write SVG density plot to %q: %v\n", o.svgOutputPath, err)
 		}
-		fmt.Printf("\t%*d\n", o.columnSize, r.count)
 	}
 }
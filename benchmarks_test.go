@@ -0,0 +1,220 @@
+package benchmarks
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseBenchTime(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantDuration time.Duration
+		wantIters    int
+		wantErr      bool
+	}{
+		{"2s", 2 * time.Second, 0, false},
+		{"500ms", 500 * time.Millisecond, 0, false},
+		{"1000x", 0, 1000, false},
+		{"1x", 0, 1, false},
+		{"not-a-duration", 0, 0, true},
+		{"Nx", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		gotDuration, gotIters, err := ParseBenchTime(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBenchTime(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if gotDuration != tt.wantDuration || gotIters != tt.wantIters {
+			t.Errorf("ParseBenchTime(%q) = (%v, %v), want (%v, %v)", tt.input, gotDuration, gotIters, tt.wantDuration, tt.wantIters)
+		}
+	}
+}
+
+func TestWithBenchTime(t *testing.T) {
+	o, err := New(NamedFunction{Name: "x", Func: func() {}}).WithBenchTime("1500x")
+	if err != nil {
+		t.Fatalf("WithBenchTime returned error: %v", err)
+	}
+	if o.iterations != 1500 || o.duration != 0 {
+		t.Errorf("WithBenchTime(%q): iterations = %d, duration = %v, want iterations=1500, duration=0", "1500x", o.iterations, o.duration)
+	}
+
+	o, err = New(NamedFunction{Name: "x", Func: func() {}}).WithBenchTime("3s")
+	if err != nil {
+		t.Fatalf("WithBenchTime returned error: %v", err)
+	}
+	if o.duration != 3*time.Second || o.iterations != 0 {
+		t.Errorf("WithBenchTime(%q): duration = %v, iterations = %d, want duration=3s, iterations=0", "3s", o.duration, o.iterations)
+	}
+
+	if _, err := New().WithBenchTime("garbage"); err == nil {
+		t.Error("WithBenchTime(\"garbage\") expected an error, got nil")
+	}
+}
+
+func TestWrapNamedFunction(t *testing.T) {
+	calls := 0
+	namedFn := NamedFunction{Name: "x", Func: func() { calls++ }}
+
+	noop := wrapNamedFunction(namedFn, 1)
+	noop.Func()
+	if calls != 1 {
+		t.Errorf("wrapNamedFunction(_, 1): calls = %d, want 1 (no-op)", calls)
+	}
+
+	calls = 0
+	wrapped := wrapNamedFunction(namedFn, 5)
+	if wrapped.Name != namedFn.Name {
+		t.Errorf("wrapNamedFunction: Name = %q, want %q", wrapped.Name, namedFn.Name)
+	}
+	wrapped.Func()
+	if calls != 5 {
+		t.Errorf("wrapNamedFunction(_, 5): calls = %d, want 5", calls)
+	}
+}
+
+func TestWithMemStatsAccounting(t *testing.T) {
+	const n = 1000
+	var sink []byte
+	fn := func() {
+		sink = make([]byte, 64)
+	}
+	results := New(NamedFunction{Name: "alloc", Func: fn}).
+		WithMemStats().
+		WithIterations(n).
+		Run()
+	_ = sink
+
+	r := results[0]
+	if r.NetAllocs == 0 {
+		t.Errorf("NetAllocs = %d, want > 0 for a function that allocates on every call", r.NetAllocs)
+	}
+	if r.NetBytes < 64 {
+		t.Errorf("NetBytes = %d, want >= 64 for a function that allocates a 64-byte slice on every call", r.NetBytes)
+	}
+}
+
+func TestBTimer(t *testing.T) {
+	// StopTimer while already stopped is a no-op.
+	b := &B{}
+	b.StopTimer()
+	if b.duration != 0 || b.timerOn {
+		t.Errorf("StopTimer on a fresh B: duration = %v, timerOn = %v, want 0, false", b.duration, b.timerOn)
+	}
+
+	// Multiple start/stop cycles accumulate.
+	b = &B{}
+	b.start = time.Now().Add(-10 * time.Millisecond)
+	b.timerOn = true
+	b.StopTimer()
+	if b.timerOn {
+		t.Error("StopTimer: timerOn = true, want false")
+	}
+	firstElapsed := b.duration
+	if firstElapsed < 10*time.Millisecond {
+		t.Errorf("StopTimer: duration = %v, want >= 10ms", firstElapsed)
+	}
+
+	b.StartTimer()
+	if !b.timerOn {
+		t.Error("StartTimer: timerOn = false, want true")
+	}
+	b.start = time.Now().Add(-5 * time.Millisecond)
+	b.StopTimer()
+	if b.duration < firstElapsed+5*time.Millisecond {
+		t.Errorf("StopTimer after a second cycle: duration = %v, want >= %v", b.duration, firstElapsed+5*time.Millisecond)
+	}
+
+	// ResetTimer while running zeroes the duration without stopping the timer.
+	b = &B{}
+	b.StartTimer()
+	b.start = time.Now().Add(-20 * time.Millisecond)
+	b.ResetTimer()
+	if b.duration != 0 {
+		t.Errorf("ResetTimer: duration = %v, want 0", b.duration)
+	}
+	if !b.timerOn {
+		t.Error("ResetTimer while running: timerOn = false, want true (still running)")
+	}
+	b.StopTimer()
+	if b.duration >= 20*time.Millisecond {
+		t.Errorf("ResetTimer: duration = %v after reset, want well under the 20ms that elapsed before the reset", b.duration)
+	}
+
+	// ResetTimer while stopped keeps the timer stopped.
+	b = &B{}
+	b.ResetTimer()
+	if b.timerOn {
+		t.Error("ResetTimer while stopped: timerOn = true, want false")
+	}
+}
+
+func TestPBNext(t *testing.T) {
+	o := New()
+	estimator := o.newEstimator()
+	var mu sync.Mutex
+	var totalTime, count int64
+	done := make(chan struct{})
+	pb := &PB{done: done, mu: &mu, estimator: estimator, totalTime: &totalTime, count: &count}
+
+	if !pb.Next() {
+		t.Fatal("first Next() = false, want true (no work done yet)")
+	}
+	time.Sleep(time.Millisecond)
+	if !pb.Next() {
+		t.Fatal("second Next() = false, want true (done not yet closed)")
+	}
+	close(done)
+	if pb.Next() {
+		t.Fatal("Next() after done is closed = true, want false")
+	}
+
+	if count != 2 {
+		t.Errorf("count = %d, want 2 (one work item recorded between each pair of Next() calls)", count)
+	}
+	if totalTime <= 0 {
+		t.Errorf("totalTime = %v, want > 0", time.Duration(totalTime))
+	}
+}
+
+func TestBenchmarkOneFuncParallelRawSamplesAndDensity(t *testing.T) {
+	namedFn := NamedFunction{Name: "pb", FuncPB: func(pb *PB) {
+		for pb.Next() {
+			time.Sleep(200 * time.Microsecond)
+		}
+	}}
+	o := New(namedFn).WithRawSamples(true).WithDensityPlot().WithDuration(20 * time.Millisecond)
+
+	r := o.benchmarkOneFuncParallel(namedFn, 1)
+	if r.Count == 0 {
+		t.Fatal("Count = 0, want > 0")
+	}
+	if r.OutlierEffect == "" {
+		t.Error("OutlierEffect is empty, want a classification since WithRawSamples is enabled")
+	}
+	if len(r.DensityX) == 0 || len(r.DensityY) == 0 {
+		t.Error("DensityX/DensityY are empty, want a computed density since WithDensityPlot is enabled")
+	}
+}
+
+func TestCalibrateInnerRepeats(t *testing.T) {
+	calls := 0
+	namedFn := NamedFunction{Name: "x", Func: func() { calls++ }}
+
+	n := calibrateInnerRepeats(namedFn)
+	if n <= 0 {
+		t.Fatalf("calibrateInnerRepeats = %d, want a positive power of two", n)
+	}
+	if n&(n-1) != 0 {
+		t.Errorf("calibrateInnerRepeats = %d, want a power of two", n)
+	}
+	if calls < n {
+		t.Errorf("calibrateInnerRepeats probed Func only %d times, want at least %d (the returned count)", calls, n)
+	}
+}
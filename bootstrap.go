@@ -0,0 +1,222 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// bootstrapResamples is the number of bootstrap resamples (R) drawn when computing BCa confidence intervals.
+const bootstrapResamples = 1000
+
+// bcaConfidenceAlpha is the two-sided significance level used for the reported confidence intervals, i.e.
+// a 95% confidence interval.
+const bcaConfidenceAlpha = 0.05
+
+// meanStatistic and medianStatistic are the statistics bcaConfidenceInterval can compute a CI for.
+func meanStatistic(samples []float64) float64 {
+	var sum float64
+	for _, s := range samples {
+		sum += s
+	}
+	return sum / float64(len(samples))
+}
+
+func medianStatistic(samples []float64) float64 {
+	sorted := slicesSortedCopy(samples)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func slicesSortedCopy(samples []float64) []float64 {
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// maxBCASampleSize caps the number of samples bcaConfidenceInterval computes over. The bootstrap resampling
+// step is O(bootstrapResamples*n) and the jackknife step makes n leave-one-out calls to statistic (each
+// itself O(n) or, for medianStatistic, O(n log n) due to the re-sort) -- O(n^2) or worse overall. A fast
+// function sampled for a full WithDuration second can produce millions of samples, which makes that
+// quadratic blowup hang indefinitely. Subsampling down to maxBCASampleSize keeps the CI computation roughly
+// constant-time while still giving a representative interval.
+const maxBCASampleSize = 2000
+
+// subsample deterministically reduces samples to at most maxN points, evenly spaced through the original
+// slice, so repeated calls over the same input are reproducible. Returns samples unchanged if it already
+// fits within maxN.
+func subsample(samples []float64, maxN int) []float64 {
+	if len(samples) <= maxN {
+		return samples
+	}
+	out := make([]float64, maxN)
+	step := float64(len(samples)) / float64(maxN)
+	for i := range out {
+		out[i] = samples[int(float64(i)*step)]
+	}
+	return out
+}
+
+// bcaConfidenceInterval computes a bias-corrected accelerated (BCa) bootstrap confidence interval for
+// statistic over samples, using bootstrapResamples resamples and the significance level bcaConfidenceAlpha.
+// samples is subsampled to maxBCASampleSize first; see its doc comment for why.
+//
+// See Efron & Tibshirani, "An Introduction to the Bootstrap" (1993), chapter 14, for the method: the bias
+// correction z0 = Φ⁻¹(#{θ*<θ̂}/R) and the jackknife-based acceleration
+// a = Σ(θ̄_(·)−θ_(i))³ / (6·(Σ(θ̄_(·)−θ_(i))²)^(3/2)) adjust the naive percentile interval to account for
+// skew in the bootstrap distribution.
+func bcaConfidenceInterval(samples []float64, statistic func([]float64) float64) [2]time.Duration {
+	samples = subsample(samples, maxBCASampleSize)
+	n := len(samples)
+	if n < 2 {
+		v := time.Duration(0)
+		if n == 1 {
+			v = time.Duration(samples[0])
+		}
+		return [2]time.Duration{v, v}
+	}
+
+	thetaHat := statistic(samples)
+
+	// Bootstrap resampling.
+	thetaStars := make([]float64, bootstrapResamples)
+	resample := make([]float64, n)
+	for r := 0; r < bootstrapResamples; r++ {
+		for i := range resample {
+			resample[i] = samples[pseudoRandomIndex(r, i, n)]
+		}
+		thetaStars[r] = statistic(resample)
+	}
+	sort.Float64s(thetaStars)
+
+	// Bias correction z0.
+	var below int
+	for _, t := range thetaStars {
+		if t < thetaHat {
+			below++
+		}
+	}
+	z0 := invNormalCDF(float64(below) / float64(bootstrapResamples))
+
+	// Jackknife acceleration a.
+	jackknife := make([]float64, n)
+	reduced := make([]float64, n-1)
+	for i := range samples {
+		copy(reduced, samples[:i])
+		copy(reduced[i:], samples[i+1:])
+		jackknife[i] = statistic(reduced)
+	}
+	var jackknifeMean float64
+	for _, j := range jackknife {
+		jackknifeMean += j
+	}
+	jackknifeMean /= float64(n)
+	var num, den float64
+	for _, j := range jackknife {
+		d := jackknifeMean - j
+		num += d * d * d
+		den += d * d
+	}
+	var a float64
+	if den > 0 {
+		a = num / (6 * math.Pow(den, 1.5))
+	}
+
+	zLow := invNormalCDF(bcaConfidenceAlpha / 2)
+	zHigh := invNormalCDF(1 - bcaConfidenceAlpha/2)
+	alpha1 := normalCDF(z0 + (z0+zLow)/(1-a*(z0+zLow)))
+	alpha2 := normalCDF(z0 + (z0+zHigh)/(1-a*(z0+zHigh)))
+
+	return [2]time.Duration{
+		time.Duration(percentile(thetaStars, alpha1)),
+		time.Duration(percentile(thetaStars, alpha2)),
+	}
+}
+
+// pseudoRandomIndex picks the bootstrap resample's i-th element index out of n, for resample round r. It is
+// deterministic (not relying on math/rand) so that runs are reproducible given the same samples.
+func pseudoRandomIndex(r, i, n int) int {
+	h := uint64(r)*2654435761 + uint64(i)*40503
+	return int(h % uint64(n))
+}
+
+// normalCDF is the standard normal cumulative distribution function Φ.
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// invNormalCDF is the inverse of the standard normal CDF, Φ⁻¹. p is clamped away from 0 and 1, where the
+// true inverse is ±∞ -- which happens in practice whenever every bootstrap resample lands on the same side
+// of θ̂ (e.g. a near-constant statistic).
+func invNormalCDF(p float64) float64 {
+	const epsilon = 1e-9
+	p = math.Min(1-epsilon, math.Max(epsilon, p))
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// percentile returns the value at the given quantile (0..1) of a pre-sorted slice. A non-finite q (which
+// can arise from a degenerate BCa adjustment, e.g. when the acceleration denominator is 0) is treated as
+// its nearest clamped bound.
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if math.IsNaN(q) {
+		q = 0
+	}
+	q = math.Min(1, math.Max(0, q))
+	idx := int(q * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// classifyOutlierVariance computes Tukey-fence-based outlier counts and an outlier variance score, following
+// the convention used by Go's own benchstat and R's bootstrap tooling: unaffected (<1%), slight (<10%),
+// moderate (<50%) or severe (>=50%), where severe means the reported mean is likely unreliable.
+func classifyOutlierVariance(samples []float64) string {
+	sorted := slicesSortedCopy(samples)
+	n := len(sorted)
+	if n < 4 {
+		return "unaffected"
+	}
+	q1 := percentile(sorted, 0.25)
+	q3 := percentile(sorted, 0.75)
+	iqr := q3 - q1
+
+	mildLow, mildHigh := q1-1.5*iqr, q3+1.5*iqr
+	severeLow, severeHigh := q1-3*iqr, q3+3*iqr
+
+	var mild, severe int
+	for _, s := range sorted {
+		switch {
+		case s < severeLow || s > severeHigh:
+			severe++
+		case s < mildLow || s > mildHigh:
+			mild++
+		}
+	}
+
+	outlierVariance := float64(mild+severe) / float64(n)
+	switch {
+	case outlierVariance < 0.01:
+		return "unaffected"
+	case outlierVariance < 0.10:
+		return "slight"
+	case outlierVariance < 0.50:
+		return "moderate"
+	default:
+		return "severe"
+	}
+}
+
+// outlierWarning returns a human-readable warning line for effect, or "" when the effect isn't severe.
+func outlierWarning(name string, effect string) string {
+	if effect != "severe" {
+		return ""
+	}
+	return fmt.Sprintf("warning: %q has severe outlier-induced variance; the reported mean may be unreliable", name)
+}
@@ -0,0 +1,53 @@
+package benchmarks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalCDFRoundTrip(t *testing.T) {
+	for _, p := range []float64{0.025, 0.1, 0.5, 0.9, 0.975} {
+		x := invNormalCDF(p)
+		got := normalCDF(x)
+		if math.Abs(got-p) > 1e-9 {
+			t.Errorf("normalCDF(invNormalCDF(%v)) = %v, want %v", p, got, p)
+		}
+	}
+}
+
+func TestMedianStatistic(t *testing.T) {
+	tests := []struct {
+		samples []float64
+		want    float64
+	}{
+		{[]float64{1, 2, 3}, 2},
+		{[]float64{1, 2, 3, 4}, 2.5},
+		{[]float64{5}, 5},
+	}
+	for _, tt := range tests {
+		if got := medianStatistic(tt.samples); got != tt.want {
+			t.Errorf("medianStatistic(%v) = %v, want %v", tt.samples, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyOutlierVariance(t *testing.T) {
+	tight := make([]float64, 100)
+	for i := range tight {
+		tight[i] = 1000 + float64(i%3)
+	}
+	if got := classifyOutlierVariance(tight); got != "unaffected" {
+		t.Errorf("classifyOutlierVariance(tight) = %q, want %q", got, "unaffected")
+	}
+
+	withOutliers := make([]float64, 100)
+	for i := range withOutliers {
+		withOutliers[i] = 1000 + float64(i%3)
+	}
+	for i := 0; i < 20; i++ {
+		withOutliers[i] = 1_000_000
+	}
+	if got := classifyOutlierVariance(withOutliers); got != "moderate" {
+		t.Errorf("classifyOutlierVariance(withOutliers) = %q, want %q", got, "moderate")
+	}
+}
@@ -0,0 +1,201 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// densityPoints is the number of points the kernel density estimate is evaluated on.
+const densityPoints = 128
+
+// sparklineLevels renders a density profile as a compact ASCII (Unicode block) column.
+var sparklineLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+// epanechnikovKernel is K(u) = ¾(1−u²) for |u|≤1, and 0 otherwise.
+func epanechnikovKernel(u float64) float64 {
+	if u < -1 || u > 1 {
+		return 0
+	}
+	return 0.75 * (1 - u*u)
+}
+
+// kernelDensityEstimate computes a kernel density estimate of samples (in nanoseconds) using the
+// Epanechnikov kernel, with bandwidth chosen by Silverman's rule of thumb h = 1.06·σ̂·n^(−1/5). It evaluates
+// the density at densityPoints points spanning [min−3h, max+3h] and returns the corresponding x values (as
+// durations) and density values.
+func kernelDensityEstimate(samples []float64) ([]time.Duration, []float64) {
+	n := len(samples)
+	if n == 0 {
+		return nil, nil
+	}
+
+	mean := meanStatistic(samples)
+	var variance float64
+	for _, s := range samples {
+		d := s - mean
+		variance += d * d
+	}
+	variance /= float64(n)
+	sigma := math.Sqrt(variance)
+
+	h := 1.06 * sigma * math.Pow(float64(n), -1.0/5.0)
+	if h <= 0 {
+		// All samples identical (or a single sample): fall back to a tiny bandwidth so the plot isn't
+		// degenerate.
+		h = 1
+	}
+
+	lo, hi := samples[0], samples[0]
+	for _, s := range samples {
+		lo = math.Min(lo, s)
+		hi = math.Max(hi, s)
+	}
+	lo -= 3 * h
+	hi += 3 * h
+	if hi <= lo {
+		hi = lo + 1
+	}
+
+	xs := make([]time.Duration, densityPoints)
+	ys := make([]float64, densityPoints)
+	step := (hi - lo) / float64(densityPoints-1)
+	for i := 0; i < densityPoints; i++ {
+		x := lo + step*float64(i)
+		var sum float64
+		for _, s := range samples {
+			sum += epanechnikovKernel((x - s) / h)
+		}
+		xs[i] = time.Duration(x)
+		ys[i] = sum / (float64(n) * h)
+	}
+	return xs, ys
+}
+
+// sparkline renders ys (assumed non-negative) as a single string of Unicode block characters, one per
+// value, scaled so that the largest value maps to a full block.
+func sparkline(ys []float64) string {
+	if len(ys) == 0 {
+		return ""
+	}
+	maxY := ys[0]
+	for _, y := range ys {
+		maxY = math.Max(maxY, y)
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+
+	var sb strings.Builder
+	levels := len(sparklineLevels) - 1
+	for _, y := range ys {
+		idx := int(y / maxY * float64(levels))
+		idx = max(0, min(levels, idx))
+		sb.WriteRune(sparklineLevels[idx])
+	}
+	return sb.String()
+}
+
+// densitySparklineSamples is how many points of the full kernel density estimate are folded down into the
+// ASCII sparkline column -- densityPoints itself would be far too wide for a terminal table.
+const densitySparklineSamples = 24
+
+// sparklineFromDensity downsamples a densityPoints-long density curve down to densitySparklineSamples
+// points before rendering it as a sparkline.
+func sparklineFromDensity(ys []float64) string {
+	if len(ys) <= densitySparklineSamples {
+		return sparkline(ys)
+	}
+	downsampled := make([]float64, densitySparklineSamples)
+	bucket := float64(len(ys)) / float64(densitySparklineSamples)
+	for i := range downsampled {
+		start := int(float64(i) * bucket)
+		end := int(float64(i+1) * bucket)
+		if end <= start {
+			end = start + 1
+		}
+		if end > len(ys) {
+			end = len(ys)
+		}
+		var sum float64
+		for _, y := range ys[start:end] {
+			sum += y
+		}
+		downsampled[i] = sum / float64(end-start)
+	}
+	return sparkline(downsampled)
+}
+
+// svgPalette cycles through a handful of distinguishable colors for overlaid density curves.
+var svgPalette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+// writeDensitySVG writes an SVG file to path with one density curve per result overlaid on a shared axis,
+// as configured by Options.WithSVGOutput. Results without a computed density (DensityY == nil) are skipped.
+func writeDensitySVG(path string, results []Result) error {
+	const width, height = 800, 400
+	const marginLeft, marginBottom = 60, 30
+
+	var minX, maxX time.Duration
+	var maxY float64
+	first := true
+	for _, r := range results {
+		for _, y := range r.DensityY {
+			maxY = math.Max(maxY, y)
+		}
+		if len(r.DensityX) == 0 {
+			continue
+		}
+		if first {
+			minX, maxX = r.DensityX[0], r.DensityX[len(r.DensityX)-1]
+			first = false
+			continue
+		}
+		minX = min(minX, r.DensityX[0])
+		maxX = max(maxX, r.DensityX[len(r.DensityX)-1])
+	}
+	if maxX <= minX {
+		maxX = minX + 1
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+
+	plotWidth := float64(width - marginLeft - 10)
+	plotHeight := float64(height - marginBottom - 10)
+	toSVGX := func(x time.Duration) float64 {
+		return float64(marginLeft) + plotWidth*float64(x-minX)/float64(maxX-minX)
+	}
+	toSVGY := func(y float64) float64 {
+		return float64(height-marginBottom) - plotHeight*y/maxY
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+
+	colorIdx := 0
+	for _, r := range results {
+		if len(r.DensityX) == 0 {
+			continue
+		}
+		color := svgPalette[colorIdx%len(svgPalette)]
+		colorIdx++
+
+		sb.WriteString(`<polyline fill="none" stroke="` + color + `" stroke-width="1.5" points="`)
+		for i, x := range r.DensityX {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%.1f,%.1f", toSVGX(x), toSVGY(r.DensityY[i]))
+		}
+		sb.WriteString(`"/>`)
+		sb.WriteByte('\n')
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" fill="%s" font-size="12">%s</text>`+"\n",
+			width-150, 20+colorIdx*16, color, r.Name)
+	}
+	sb.WriteString(`</svg>` + "\n")
+
+	return os.WriteFile(path, []byte(sb.String()), 0o644)
+}
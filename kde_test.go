@@ -0,0 +1,49 @@
+package benchmarks
+
+import "testing"
+
+func TestKernelDensityEstimate(t *testing.T) {
+	samples := make([]float64, 200)
+	for i := range samples {
+		samples[i] = 1000 + float64(i%5)
+	}
+	xs, ys := kernelDensityEstimate(samples)
+
+	if len(xs) != densityPoints || len(ys) != densityPoints {
+		t.Fatalf("kernelDensityEstimate returned %d x / %d y points, want %d each", len(xs), len(ys), densityPoints)
+	}
+	for i, y := range ys {
+		if y < 0 {
+			t.Errorf("ys[%d] = %v, want a non-negative density", i, y)
+		}
+	}
+	if xs[0] >= xs[len(xs)-1] {
+		t.Errorf("xs is not increasing: xs[0]=%v, xs[last]=%v", xs[0], xs[len(xs)-1])
+	}
+}
+
+func TestKernelDensityEstimateEmpty(t *testing.T) {
+	xs, ys := kernelDensityEstimate(nil)
+	if xs != nil || ys != nil {
+		t.Errorf("kernelDensityEstimate(nil) = (%v, %v), want (nil, nil)", xs, ys)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline(nil); got != "" {
+		t.Errorf("sparkline(nil) = %q, want empty string", got)
+	}
+
+	ys := []float64{0, 0.5, 1}
+	got := sparkline(ys)
+	wantLen := len(ys)
+	if len([]rune(got)) != wantLen {
+		t.Errorf("sparkline(%v) = %q, want %d runes", ys, got, wantLen)
+	}
+	if []rune(got)[0] != sparklineLevels[0] {
+		t.Errorf("sparkline(%v)[0] = %q, want the lowest level %q", ys, string([]rune(got)[0]), string(sparklineLevels[0]))
+	}
+	if []rune(got)[2] != sparklineLevels[len(sparklineLevels)-1] {
+		t.Errorf("sparkline(%v)[2] = %q, want the highest level %q", ys, string([]rune(got)[2]), string(sparklineLevels[len(sparklineLevels)-1]))
+	}
+}
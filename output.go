@@ -0,0 +1,129 @@
+package benchmarks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// renderText prints a human-readable, tab-aligned table to o.writer -- the default rendering used by Done.
+func (o *Options) renderText(results []Result) {
+	// First column
+	header := "Benchmarks:"
+	maxLen := len(header)
+	runeCount := make([]int, len(results))
+	for ii, r := range results {
+		runeCount[ii] = utf8.RuneCountInString(r.Name)
+		maxLen = max(maxLen, runeCount[ii])
+	}
+
+	// Header
+	extraSpaces := maxLen - len(header)
+	if extraSpaces > 0 {
+		header = header + strings.Repeat(" ", extraSpaces)
+	}
+	fmt.Fprintf(o.writer, "%s\t%*s\t%*s", header, o.columnSize, "Mean", o.columnSize, "Median")
+	for _, q := range o.quantiles {
+		fmt.Fprintf(o.writer, "\t%*s", o.columnSize, fmt.Sprintf("%d%%-tile", q))
+	}
+	countStr := "Count"
+	switch {
+	case o.autoInnerRepeats:
+		countStr = "Runs(x auto)"
+	case o.innerRepeats > 1:
+		countStr = fmt.Sprintf("Runs(x%d)", o.innerRepeats)
+	}
+	fmt.Fprintf(o.writer, "\t%*s", o.columnSize, countStr)
+	if o.memStats {
+		fmt.Fprintf(o.writer, "\t%*s\t%*s", o.columnSize, "allocs/op", o.columnSize, "bytes/op")
+	}
+	if o.densityPlot {
+		fmt.Fprintf(o.writer, "\t%*s", o.columnSize, "Density")
+	}
+	fmt.Fprintln(o.writer)
+
+	for ii, r := range results {
+		// Pretty-print.
+		name := r.Name
+		extraSpaces := maxLen - runeCount[ii]
+		if extraSpaces > 0 {
+			name = name + strings.Repeat(" ", extraSpaces)
+		}
+		fmt.Fprintf(o.writer, "%s\t%*s\t%*s", name, o.columnSize, o.prettyPrintFn(r.Mean), o.columnSize, o.prettyPrintFn(r.Median))
+		for _, q := range r.Quantiles {
+			fmt.Fprintf(o.writer, "\t%*s", o.columnSize, o.prettyPrintFn(q))
+		}
+		fmt.Fprintf(o.writer, "\t%*d", o.columnSize, r.Count)
+		if o.memStats {
+			fmt.Fprintf(o.writer, "\t%*d\t%*d", o.columnSize, r.NetAllocs, o.columnSize, r.NetBytes)
+		}
+		if o.densityPlot {
+			fmt.Fprintf(o.writer, "\t%*s", o.columnSize, r.DensitySparkline)
+		}
+		fmt.Fprintln(o.writer)
+
+		if o.rawSamples {
+			fmt.Fprintf(o.writer, "\t95%% CI mean: [%s, %s]\t95%% CI median: [%s, %s]\toutlier effect: %s\n",
+				o.prettyPrintFn(r.MeanCI[0]), o.prettyPrintFn(r.MeanCI[1]),
+				o.prettyPrintFn(r.MedianCI[0]), o.prettyPrintFn(r.MedianCI[1]), r.OutlierEffect)
+			if warning := outlierWarning(r.Name, r.OutlierEffect); warning != "" {
+				fmt.Fprintln(o.writer, warning)
+			}
+		}
+	}
+}
+
+// renderJSON writes results to o.writer as a JSON array, one object per Result.
+func (o *Options) renderJSON(results []Result) {
+	enc := json.NewEncoder(o.writer)
+	enc.SetIndent("", "  ")
+	// Encoding errors are not actionable here: o.writer is caller-supplied and Done has no error return.
+	_ = enc.Encode(results)
+}
+
+// renderCSV writes results to o.writer as comma-separated values, one row per Result. The quantile columns
+// are labeled "p<percent>_ns" (e.g. "p99_ns") and follow o.quantiles' order.
+func (o *Options) renderCSV(results []Result) {
+	w := csv.NewWriter(o.writer)
+	defer w.Flush()
+
+	header := []string{"name", "mean_ns", "median_ns"}
+	for _, q := range o.quantiles {
+		header = append(header, fmt.Sprintf("p%d_ns", q))
+	}
+	header = append(header, "count")
+	if o.memStats {
+		header = append(header, "allocs_per_op", "bytes_per_op")
+	}
+	_ = w.Write(header)
+
+	for _, r := range results {
+		row := []string{r.Name, strconv.FormatInt(int64(r.Mean), 10), strconv.FormatInt(int64(r.Median), 10)}
+		for _, q := range r.Quantiles {
+			row = append(row, strconv.FormatInt(int64(q), 10))
+		}
+		row = append(row, strconv.Itoa(r.Count))
+		if o.memStats {
+			row = append(row, strconv.FormatUint(r.NetAllocs, 10), strconv.FormatUint(r.NetBytes, 10))
+		}
+		_ = w.Write(row)
+	}
+}
+
+// renderGoBench writes results to o.writer using the same line format as `go test -bench`, so the output
+// can be piped straight into benchstat:
+//
+//	BenchmarkXxx-8    12345    678.00 ns/op    12 B/op    3 allocs/op
+func (o *Options) renderGoBench(results []Result) {
+	for _, r := range results {
+		name := strings.ReplaceAll(r.Name, " ", "")
+		fmt.Fprintf(o.writer, "Benchmark%s-%d\t%d\t%.2f ns/op", name, r.GOMAXPROCS, r.Count, float64(r.Mean))
+		if o.memStats {
+			fmt.Fprintf(o.writer, "\t%d B/op\t%d allocs/op", r.NetBytes, r.NetAllocs)
+		}
+		fmt.Fprintln(o.writer)
+	}
+}
@@ -0,0 +1,67 @@
+package benchmarks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testResults() []Result {
+	return []Result{
+		{
+			Name:             "Foo",
+			Mean:             100 * time.Nanosecond,
+			Median:           90 * time.Nanosecond,
+			QuantilePercents: []int{5, 99},
+			Quantiles:        []time.Duration{80 * time.Nanosecond, 150 * time.Nanosecond},
+			Count:            10,
+			NetAllocs:        2,
+			NetBytes:         64,
+		},
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	var buf bytes.Buffer
+	o := New().WithWriter(&buf)
+	o.renderJSON(testResults())
+
+	got := buf.String()
+	for _, want := range []string{`"Name": "Foo"`, `"Mean": 100`, `"Count": 10`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderJSON output missing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	var buf bytes.Buffer
+	o := New().WithWriter(&buf).WithMemStats()
+	o.renderCSV(testResults())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("renderCSV: got %d lines, want 2 (header + 1 row):\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "name,mean_ns,median_ns,p5_ns,p99_ns,count,allocs_per_op,bytes_per_op") {
+		t.Errorf("renderCSV header = %q, want it to start with the expected columns", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Foo,100,90,80,150,10,2,64") {
+		t.Errorf("renderCSV row = %q, want it to start with the expected values", lines[1])
+	}
+}
+
+func TestRenderGoBench(t *testing.T) {
+	var buf bytes.Buffer
+	o := New().WithWriter(&buf).WithMemStats()
+	o.renderGoBench(testResults())
+
+	got := buf.String()
+	if !strings.Contains(got, "BenchmarkFoo-") {
+		t.Errorf("renderGoBench output missing %q, got:\n%s", "BenchmarkFoo-", got)
+	}
+	if !strings.Contains(got, "100.00 ns/op") || !strings.Contains(got, "64 B/op") || !strings.Contains(got, "2 allocs/op") {
+		t.Errorf("renderGoBench output missing expected fields, got:\n%s", got)
+	}
+}